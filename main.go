@@ -2,20 +2,25 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
+	"context"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
-	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	log "github.com/sirupsen/logrus"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
+	"golang.org/x/time/rate"
+
+	"github.com/MynaITLabs/subsnipe/sources"
 )
 
 // cnameResult stores the result of a CNAME query for a domain
@@ -26,14 +31,28 @@ type cnameResult struct {
 }
 
 var (
-	found    		 	  []string
 	notFound 		 	  []string
     outputFileName   	  string  	= "output.md"
 	domain           	  string
-	isExploitable         []string
-	notExploitable        []string
-	unknownExploitability []string
+	findings              []Finding
+	resultsMu             sync.Mutex
 	fingerprintsFile      = filepath.Join("fingerprints", "can-i-take-over-xyz_fingerprints.json")
+
+	qps float64
+
+	formatFlag string
+
+	dnsServer     string
+	resolversFile string
+	resolvers     []string
+	resolverIdx   uint64
+
+	sourcesFlag    string
+	wordlistFile   string
+	passiveDNSFile string
+
+	noVerify      bool
+	verifyTimeout time.Duration
 )
 
 func main() {
@@ -47,6 +66,20 @@ func main() {
 	rootCmd.Flags().StringVarP(&domain, "domain", "d", "", "The domain to query for subdomains (required)")
 	rootCmd.MarkFlagRequired("domain")
 
+	rootCmd.Flags().StringVarP(&dnsServer, "dns", "D", "8.8.8.8", "DNS server to use for CNAME resolution")
+	rootCmd.Flags().StringVarP(&resolversFile, "resolvers", "r", "", "File with a newline-separated list of DNS servers to round-robin across")
+
+	rootCmd.Flags().StringVar(&sourcesFlag, "sources", "crtsh", "Comma-separated list of enumeration sources to use (crtsh,hackertarget,otx,rapiddns)")
+	rootCmd.Flags().StringVarP(&wordlistFile, "wordlist", "w", "", "File with a newline-separated list of candidate subdomains to brute-force against the domain")
+	rootCmd.Flags().StringVar(&passiveDNSFile, "passive-dns-file", "", "Passive DNS file to enumerate subdomains from, in addition to --sources")
+
+	rootCmd.Flags().BoolVar(&noVerify, "no-verify", false, "Skip active takeover verification and classify exploitability from fingerprint matches alone")
+	rootCmd.Flags().DurationVar(&verifyTimeout, "verify-timeout", 10*time.Second, "Timeout for the HTTP client used during active takeover verification")
+
+	rootCmd.Flags().StringVarP(&formatFlag, "format", "f", "md", "Comma-separated output formats to write (md,json,csv,sarif)")
+
+	rootCmd.Flags().Float64Var(&qps, "qps", 20, "Maximum DNS/HTTP queries per second, to avoid getting the resolver rate-limited")
+
 	if err := rootCmd.Execute(); err != nil {
 		log.Fatalf("Error executing subSnipe: %s", err)
 	}
@@ -62,10 +95,9 @@ func run(cmd *cobra.Command, args []string) {
 	// check if a later version of this tool exists
 	NotifyOfUpdates()
 
-	if !checkDigAvailable() {
-        log.Fatal("The 'dig' command is not available. Please ensure it is installed.")
-		return
-    }
+	if err := loadResolvers(); err != nil {
+		log.Fatalf("Error loading resolvers: %v", err)
+	}
 
 	// if the app runs inside a docker container, the output has to be written into `./output/output.md`, because
 	// we will mount the CWD inside the container into `./output/` 
@@ -84,47 +116,132 @@ func run(cmd *cobra.Command, args []string) {
 	}
 
 	log.Info("Checking subdomains for: ", domain)
-	queryCRTSH()
-}
 
-// Queries crt.sh for subdomains of the given domain and writes unique common names to a file
-func queryCRTSH() {
-	log.Info("Querying crt.sh for subdomains... (may take a moment)")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	go func() {
+		<-sigCh
+		log.Warn("Received interrupt, cancelling in-flight requests...")
+		cancel()
+	}()
 
-	url := fmt.Sprintf("https://crt.sh/?q=%s&output=json", domain)
-	resp, err := http.Get(url)
+	subdomainsFilePath, err := enumerateSubdomains(ctx)
 	if err != nil {
-		log.Error("Error querying crt.sh: ", err)
-		return
+		log.Fatalf("Error enumerating subdomains: %v", err)
 	}
-	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	checkCNAMEs(ctx, subdomainsFilePath)
+}
+
+// enumerateSubdomains runs every enabled EnumSource (and, if given, the wordlist) for
+// `domain`, de-duplicates the results across all of them, and writes the unique set to
+// a file that checkCNAMEs can stream from.
+func enumerateSubdomains(ctx context.Context) (string, error) {
+	enabled, err := sources.Resolve(strings.Split(sourcesFlag, ","))
+	if err != nil {
+		return "", err
+	}
+	if passiveDNSFile != "" {
+		enabled = append(enabled, sources.NewPassiveDNSSource(passiveDNSFile))
+	}
+
+	var wg sync.WaitGroup
+	merged := make(chan string, 100)
+
+	for _, source := range enabled {
+		wg.Add(1)
+		go func(source sources.EnumSource) {
+			defer wg.Done()
+
+			// Enumerate itself does the source's (possibly slow) HTTP request, so it must run
+			// inside this goroutine rather than before it - otherwise sources are still queried
+			// one after another instead of concurrently.
+			names, err := source.Enumerate(ctx, domain)
+			if err != nil {
+				log.Errorf("Error enumerating with source %s: %v", source.Name(), err)
+				return
+			}
+
+			for name := range names {
+				merged <- name
+			}
+			log.Infof("Source %s finished enumerating", source.Name())
+		}(source)
+	}
+
+	if wordlistFile != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := enumerateWordlist(ctx, wordlistFile, merged); err != nil {
+				log.Errorf("Error reading wordlist: %v", err)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	seen := make(map[string]struct{})
+	subdomainsFilePath := "subdomains.txt"
+	subdomainsFile, err := os.Create(subdomainsFilePath)
 	if err != nil {
-		log.Error("Error reading response body: ", err)
-		return
+		return "", fmt.Errorf("creating subdomains file: %w", err)
 	}
+	defer subdomainsFile.Close()
+
+	writer := bufio.NewWriter(subdomainsFile)
+	defer writer.Flush()
 
-	var data []map[string]interface{}
-	if err := json.Unmarshal(body, &data); err != nil {
-		log.Error("Error unmarshaling JSON: ", err)
-		return
+	for name := range merged {
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+		fmt.Fprintln(writer, name)
 	}
 
-	uniqueCommonNames := extractUniqueCommonNames(data)
+	log.Infof("%d unique subdomains have been extracted to %s", len(seen), subdomainsFilePath)
+	return subdomainsFilePath, nil
+}
 
-	subdomainsFilePath := "crt-subdomains.txt"
-	if err := writeSubdomainsToFile(uniqueCommonNames, subdomainsFilePath); err != nil {
-		log.Error("Error writing to file: ", err)
-		return
+// enumerateWordlist reads candidate subdomain labels from path, joins each with domain, and
+// feeds them into out for brute-force enumeration alongside the passive sources.
+func enumerateWordlist(ctx context.Context, path string, out chan<- string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("opening wordlist: %w", err)
 	}
+	defer file.Close()
 
-	log.Info("Unique common names have been extracted to ", subdomainsFilePath)
-	checkCNAMEs(subdomainsFilePath)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		label := strings.TrimSpace(scanner.Text())
+		if label == "" {
+			continue
+		}
+
+		select {
+		case out <- fmt.Sprintf("%s.%s", label, domain):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return scanner.Err()
 }
 
-// Reads subdomains from a file and queries for their CNAME records concurrently
-func checkCNAMEs(subdomainsFilePath string) {
+// workerCount is the number of concurrent workers draining the CNAME job queue.
+const workerCount = 20
+
+// Reads subdomains from a file and queries for their CNAME records via a bounded worker pool,
+// rate-limited to --qps and cancellable through ctx.
+func checkCNAMEs(ctx context.Context, subdomainsFilePath string) {
     log.Info("Querying CNAME records for subdomains...")
 
     subdomainsFile, err := os.Open(subdomainsFilePath)
@@ -134,175 +251,258 @@ func checkCNAMEs(subdomainsFilePath string) {
     }
     defer subdomainsFile.Close()
 
-    scanner := bufio.NewScanner(subdomainsFile)
-    var wg sync.WaitGroup
-    results := make(chan cnameResult, 100) // Buffer may be adjusted based on expected concurrency
-
-    maxConcurrency := 20
-    sem := make(chan struct{}, maxConcurrency) // Control concurrency with a semaphore
-
     fingerprints, err := loadFingerprints(fingerprintsFile)
     if err != nil {
         log.Fatalf("Error loading fingerprints: %v", err)
     }
 
-    // Launch a goroutine to process results concurrently
-    go func() {
-        for result := range results {
-            processCNAMEResult(result, fingerprints)
-        }
-    }()
+    limiter := rate.NewLimiter(rate.Limit(qps), workerCount)
+    jobs := make(chan string, 100)
 
-    for scanner.Scan() {
-        domain := scanner.Text()
+    var wg sync.WaitGroup
+    for i := 0; i < workerCount; i++ {
         wg.Add(1)
-        sem <- struct{}{} // Acquire semaphore
-
-        // Launch a goroutine for each CNAME query
-        go func(domain string) {
+        go func() {
             defer wg.Done()
-            defer func() { <-sem }() // Release semaphore
-            queryAndSendCNAME(domain, results)
-        }(domain)
+            for sub := range jobs {
+                if err := limiter.Wait(ctx); err != nil {
+                    return // ctx was cancelled
+                }
+
+                result := queryCNAME(ctx, sub)
+                processCNAMEResult(ctx, limiter, result, fingerprints)
+            }
+        }()
+    }
+
+    scanner := bufio.NewScanner(subdomainsFile)
+scanLoop:
+    for scanner.Scan() {
+        select {
+        case jobs <- scanner.Text():
+        case <-ctx.Done():
+            break scanLoop
+        }
     }
+    close(jobs)
 
     if err := scanner.Err(); err != nil {
         log.Error("Error reading from file: ", err)
-        return
     }
 
-    // Wait for all queries to finish
+    // Wait for all workers to finish
     wg.Wait()
 
-    // Close the results channel after all queries are complete
-    close(results)
-
     // Write results after processing
     writeResults()
 }
 
-// Performs a CNAME query for a given domain and sends the result to the results channel
-func queryAndSendCNAME(domain string, results chan<- cnameResult) {
-    cname, err := exec.Command("dig", "+short", "CNAME", domain).Output()
-    if err != nil || len(cname) == 0 {
-        results <- cnameResult{domain: domain, err: fmt.Errorf("no CNAME record found or dig command failed")}
-    } else {
-        // Log the found CNAME
-        log.Infof("CNAME found for %s is: %s", domain, strings.TrimSpace(string(cname)))
-        results <- cnameResult{domain: domain, cname: strings.TrimSpace(string(cname))}
-    }
-}
+// loadResolvers populates the package-level resolvers slice from the --resolvers file if one is
+// given, and otherwise from --dns alone; --dns is not added to a list read from a file.
+// The resolvers are round-robined across the concurrent worker pool by nextResolver.
+func loadResolvers() error {
+	if resolversFile == "" {
+		resolvers = []string{dnsServer}
+		return nil
+	}
+
+	file, err := os.Open(resolversFile)
+	if err != nil {
+		return fmt.Errorf("opening resolvers file: %w", err)
+	}
+	defer file.Close()
 
-// Processes CNAME query results from the results channel, sorting them into found and not found
-func processResults(results <-chan cnameResult) {
-	for result := range results {
-		if result.err != nil || result.cname == "" {
-			notFoundMsg := fmt.Sprintf("No CNAME record found for: %s", result.domain)
-			log.Warnf(notFoundMsg)
-			notFound = append(notFound, notFoundMsg)
-		} else {
-			foundMsg := fmt.Sprintf("CNAME for %s is: %s", result.domain, result.cname)
-			log.Infof(foundMsg)
-			found = append(found, foundMsg)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
 		}
+		resolvers = append(resolvers, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading resolvers file: %w", err)
+	}
+	if len(resolvers) == 0 {
+		return fmt.Errorf("resolvers file %s contained no resolvers", resolversFile)
 	}
 
-	log.Info("... Finished querying CNAMEs")
+	return nil
 }
 
-// Writes the sorted CNAME query results to an output markdown file with categorization based on exploitability
-func writeResults() {
-    outputFile, err := os.Create(outputFileName)
+// nextResolver round-robins across the configured resolvers so concurrent workers spread
+// their queries across the whole list instead of hammering a single upstream server.
+func nextResolver() string {
+	idx := atomic.AddUint64(&resolverIdx, 1)
+	return resolvers[int(idx)%len(resolvers)]
+}
+
+// newResolver builds a net.Resolver that dials the given DNS server instead of the system default,
+// so we no longer depend on the `dig` binary being installed.
+func newResolver(server string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
+			d := net.Dialer{Timeout: 5 * time.Second}
+			return d.DialContext(ctx, network, net.JoinHostPort(server, "53"))
+		},
+	}
+}
+
+// Performs a CNAME query for a given domain, honouring ctx cancellation
+func queryCNAME(ctx context.Context, domain string) cnameResult {
+    resolver := newResolver(nextResolver())
+
+    queryCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+    defer cancel()
+
+    cname, err := resolver.LookupCNAME(queryCtx, domain)
     if err != nil {
-        log.Fatalf("Error creating output file: %v", err)
+        return cnameResult{domain: domain, err: fmt.Errorf("no CNAME record found: %w", err)}
+    } else if cname == "" || cname == domain+"." {
+        return cnameResult{domain: domain, err: fmt.Errorf("no CNAME record found for %s", domain)}
     }
-    defer outputFile.Close()
 
-    // Writing Is Exploitable section
-    if len(isExploitable) > 0 {
-        outputFile.WriteString("### Is Exploitable\n\n")
-        for _, item := range isExploitable {
-            outputFile.WriteString("- " + item + "\n")
+    // Log the found CNAME
+    log.Infof("CNAME found for %s is: %s", domain, cname)
+    return cnameResult{domain: domain, cname: cname}
+}
+
+// Writes the accumulated findings to every format requested via --format (md,json,csv,sarif)
+func writeResults() {
+    base := strings.TrimSuffix(outputFileName, filepath.Ext(outputFileName))
+
+    for _, format := range strings.Split(formatFlag, ",") {
+        format = strings.ToLower(strings.TrimSpace(format))
+        if format == "" {
+            continue
         }
-        outputFile.WriteString("\n")
-    }
 
-    // Writing Not Exploitable section
-    if len(notExploitable) > 0 {
-        outputFile.WriteString("### Not Exploitable\n\n")
-        for _, item := range notExploitable {
-            outputFile.WriteString("- " + item + "\n")
+        var path string
+        var err error
+        switch format {
+        case "md":
+            path = outputFileName
+            err = writeMarkdownReport(path, findings)
+        case "json":
+            path = base + ".json"
+            err = writeJSONReport(path, findings)
+        case "csv":
+            path = base + ".csv"
+            err = writeCSVReport(path, findings)
+        case "sarif":
+            path = base + ".sarif"
+            err = writeSARIFReport(path, findings)
+        default:
+            err = fmt.Errorf("unknown output format %q", format)
         }
-        outputFile.WriteString("\n")
-    }
 
-    // Writing Exploitability Unknown section
-    if len(unknownExploitability) > 0 {
-        outputFile.WriteString("### Exploitability Unknown\n\n")
-        for _, item := range unknownExploitability {
-            outputFile.WriteString("- " + item + "\n")
+        if err != nil {
+            log.Errorf("Error writing %s report: %v", format, err)
+            continue
         }
+        log.Println("Results have been written to", path)
     }
-
-    log.Println("Results have been written to", outputFileName)
 }
 
-// Searches for a CNAME in the fingerprints and checks its vulnerability status.
-func isVulnerableCNAME(cname string, fingerprints map[string]map[string]interface{}) (bool, bool) {
+// Searches for a CNAME in the fingerprints and checks its vulnerability status. Also returns
+// the matched service name and the fingerprint's verification text and NXDOMAIN flag so
+// callers can drive checkTakeover and populate a Finding.
+func isVulnerableCNAME(cname string, fingerprints map[string]map[string]interface{}) (bool, bool, string, string, bool) {
     // Trim the trailing dot from the cname if present
     cname = strings.TrimSuffix(cname, ".")
-    
-    for _, fingerprint := range fingerprints {
+
+    for service, fingerprint := range fingerprints {
         cnameList := fingerprint["cname"].([]interface{})
         for _, c := range cnameList {
             pattern := c.(string)
             if strings.HasSuffix(cname, pattern) {
-                return true, fingerprint["vulnerable"].(bool)
+                fingerprintText, _ := fingerprint["fingerprint"].(string)
+                hasNXDOMAINFlag, _ := fingerprint["nxdomain"].(bool)
+                return true, fingerprint["vulnerable"].(bool), service, fingerprintText, hasNXDOMAINFlag
             }
         }
     }
-    return false, false // CNAME not found in fingerprints
+    return false, false, "", "", false // CNAME not found in fingerprints
 }
 
 // processCNAMEResult processes each CNAME query result, checking against fingerprints and service names
-func processCNAMEResult(result cnameResult, fingerprints map[string]map[string]interface{}) {
+func processCNAMEResult(ctx context.Context, limiter *rate.Limiter, result cnameResult, fingerprints map[string]map[string]interface{}) {
     if result.err != nil || result.cname == "" {
         notFoundMsg := fmt.Sprintf("No CNAME record found for: %s", result.domain)
         log.Warnf(notFoundMsg)
+        resultsMu.Lock()
         notFound = append(notFound, notFoundMsg)
+        resultsMu.Unlock()
         return
     }
 
-    directMatch, vulnerable := isVulnerableCNAME(result.cname, fingerprints)
+    directMatch, vulnerable, service, fingerprintText, hasNXDOMAINFlag := isVulnerableCNAME(result.cname, fingerprints)
     if directMatch {
-        foundMsg := fmt.Sprintf("CNAME for %s is: %s (found matching fingerprint - %s)", result.domain, result.cname, ifThenElse(vulnerable, "vulnerable", "safe"))
-        appendResultBasedOnVulnerability(vulnerable, foundMsg)
+        recordExploitability(ctx, limiter, result, service, vulnerable, fingerprintText, hasNXDOMAINFlag)
     } else {
         // Handle the case where the service might be identified by its second-level domain in the fingerprints
         sld := extractServiceName(result.cname)
         if serviceMatch, vulnerable, service, fingerprintText, hasNXDOMAINFlag := isServiceVulnerable(sld, fingerprints); serviceMatch {
-            serviceMsg := fmt.Sprintf("CNAME for %s is: %s (found potentially matching service '%s' - %s)", result.domain, result.cname, service, ifThenElse(vulnerable, "vulnerable", "safe"))
-            appendResultBasedOnVulnerability(vulnerable, serviceMsg)
+            recordExploitability(ctx, limiter, result, service, vulnerable, fingerprintText, hasNXDOMAINFlag)
         } else {
-            unknownMsg := fmt.Sprintf("CNAME for %s is: %s", result.domain, result.cname)
-            unknownExploitability = append(unknownExploitability, unknownMsg)
+            appendFinding(Finding{Domain: result.domain, CNAME: result.cname})
         }
     }
 }
 
+// recordExploitability turns a fingerprint match into a Finding and appends it to findings.
+// When active verification is enabled (the default), a fingerprint match on its own is not
+// enough to call something exploitable - checkTakeover must also succeed against the live
+// domain. That call is rate-limited by the same --qps limiter as the DNS lookups, since it
+// makes its own network request(s) against the target. The network call this involves runs
+// unlocked so one worker's slow verification doesn't stall every other worker in the pool;
+// only the final append is guarded.
+func recordExploitability(ctx context.Context, limiter *rate.Limiter, result cnameResult, service string, vulnerable bool, fingerprintText string, hasNXDOMAINFlag bool) {
+    finding := Finding{
+        Domain:     result.domain,
+        CNAME:      result.cname,
+        Service:    service,
+        Vulnerable: vulnerable,
+    }
+
+    switch {
+    case !vulnerable:
+        finding.Evidence = "Matched a fingerprint marked safe"
+    case noVerify:
+        finding.Evidence = "Fingerprint match only (--no-verify)"
+    case limiter.Wait(ctx) == nil && checkTakeover(ctx, result.domain, fingerprintText, hasNXDOMAINFlag):
+        finding.Verified = true
+        finding.Evidence = "Active takeover verification succeeded"
+    default:
+        finding.Evidence = "Fingerprint matched but active verification failed"
+    }
+
+    appendFinding(finding)
+}
+
+// appendFinding is the only place that mutates the shared findings slice, so the lock it
+// takes never spans network I/O.
+func appendFinding(f Finding) {
+    resultsMu.Lock()
+    findings = append(findings, f)
+    resultsMu.Unlock()
+}
+
 // Checks if the domain pointed by the CNAME is take-over-able
-func checkTakeover(domain string, fingerprintText string, hasNXDOMAINFlag bool) bool {
+func checkTakeover(ctx context.Context, domain string, fingerprintText string, hasNXDOMAINFlag bool) bool {
 	if hasNXDOMAINFlag {
-		return checkTakeoverDNS(domain, fingerprintText)
+		return checkTakeoverDNS(ctx, domain, fingerprintText)
 	} else {
-		return checkTakeoverHTTP(domain, fingerprintText)
+		return checkTakeoverHTTP(ctx, domain, fingerprintText)
 	}
 }
 
 // Checks if the domain pointed by the CNAME is take-over-able by performing a DNS query
-func checkTakeoverDNS(domain string, fingerprintText string) bool {
+func checkTakeoverDNS(ctx context.Context, domain string, fingerprintText string) bool {
     cname := fmt.Sprintf("_cname.%s", domain) // Construct the CNAME query
-    _, err := net.LookupCNAME(cname)
+    resolver := newResolver(nextResolver())
+    _, err := resolver.LookupCNAME(ctx, cname)
 
     if err != nil {
         if dnsErr, ok := err.(*net.DNSError); ok && dnsErr.IsNotFound { // Check if NXDOMAIN error
@@ -313,9 +513,16 @@ func checkTakeoverDNS(domain string, fingerprintText string) bool {
     return false
 }
 
-func checkTakeoverHTTP(domain string, fingerprintText string) bool {
+func checkTakeoverHTTP(ctx context.Context, domain string, fingerprintText string) bool {
 	url := "http://" + domain
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		log.Errorf("Error building HTTP request for %s: %v", url, err)
+		return false
+	}
+
+	client := &http.Client{Timeout: verifyTimeout}
+	resp, err := client.Do(req)
 	if err != nil {
 		log.Errorf("Error making HTTP request to %s: %v", url, err)
 		return false
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Finding is a structured record of a single subdomain's takeover exploitability, produced by
+// processCNAMEResult and rendered into one or more output formats by writeResults.
+type Finding struct {
+	Domain     string `json:"domain"`
+	CNAME      string `json:"cname"`
+	Service    string `json:"service"`
+	Vulnerable bool   `json:"vulnerable"`
+	Verified   bool   `json:"verified"`
+	Evidence   string `json:"evidence"`
+}
+
+// findingCategory buckets a Finding into the Markdown report's sections: an unmatched CNAME is
+// of unknown exploitability, a vulnerable match is exploitable once verified (or always, under
+// --no-verify) and of unknown exploitability while unverified, and a match against a fingerprint
+// marked safe is considered not exploitable.
+func findingCategory(f Finding) string {
+	if f.Service == "" {
+		return "unknown"
+	}
+	if f.Vulnerable && (f.Verified || noVerify) {
+		return "exploitable"
+	}
+	if f.Vulnerable {
+		return "unknown"
+	}
+	return "notExploitable"
+}
+
+// writeMarkdownReport writes findings to path as the existing "Is Exploitable" / "Not
+// Exploitable" / "Exploitability Unknown" Markdown report.
+func writeMarkdownReport(path string, findings []Finding) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	sections := []struct {
+		title    string
+		category string
+	}{
+		{"### Is Exploitable\n\n", "exploitable"},
+		{"### Not Exploitable\n\n", "notExploitable"},
+		{"### Exploitability Unknown\n\n", "unknown"},
+	}
+
+	for _, section := range sections {
+		var lines []string
+		for _, f := range findings {
+			if findingCategory(f) != section.category {
+				continue
+			}
+			lines = append(lines, formatFindingLine(f))
+		}
+		if len(lines) == 0 {
+			continue
+		}
+
+		outputFile.WriteString(section.title)
+		for _, line := range lines {
+			outputFile.WriteString("- " + line + "\n")
+		}
+		outputFile.WriteString("\n")
+	}
+
+	return nil
+}
+
+// formatFindingLine renders a Finding the way the old ad-hoc message strings used to read.
+func formatFindingLine(f Finding) string {
+	line := fmt.Sprintf("CNAME for %s is: %s", f.Domain, f.CNAME)
+	if f.Service != "" {
+		line += fmt.Sprintf(" (service '%s')", f.Service)
+	}
+	if f.Evidence != "" {
+		line += fmt.Sprintf(" - %s", f.Evidence)
+	}
+	return line
+}
+
+// writeJSONReport writes findings to path as a JSON array, for pipeline consumers that would
+// otherwise have to regex the Markdown report.
+func writeJSONReport(path string, findings []Finding) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	encoder := json.NewEncoder(outputFile)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(findings)
+}
+
+// writeCSVReport writes findings to path as CSV, one row per Finding.
+func writeCSVReport(path string, findings []Finding) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	writer := csv.NewWriter(outputFile)
+	defer writer.Flush()
+
+	if err := writer.Write([]string{"domain", "cname", "service", "vulnerable", "verified", "evidence"}); err != nil {
+		return fmt.Errorf("writing CSV header: %w", err)
+	}
+
+	for _, f := range findings {
+		row := []string{f.Domain, f.CNAME, f.Service, fmt.Sprintf("%t", f.Vulnerable), fmt.Sprintf("%t", f.Verified), f.Evidence}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("writing CSV row: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// sarifLog and friends model the minimal subset of the SARIF 2.1.0 schema SubSnipe needs to
+// surface findings in GitHub code-scanning.
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool    `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type sarifResult struct {
+	RuleID    string              `json:"ruleId"`
+	Level     string              `json:"level"`
+	Message   sarifMessage        `json:"message"`
+	Locations []sarifLocation     `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifLogicalLocation struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+const sarifRuleID = "subsnipe/possible-subdomain-takeover"
+
+// writeSARIFReport writes findings to path as SARIF, so exploitable findings show up as
+// GitHub code-scanning alerts when SubSnipe is run in CI.
+func writeSARIFReport(path string, findings []Finding) error {
+	outputFile, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating output file: %w", err)
+	}
+	defer outputFile.Close()
+
+	report := sarifLog{
+		Schema:  "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/master/Schemata/sarif-schema-2.1.0.json",
+		Version: "2.1.0",
+		Runs: []sarifRun{
+			{
+				Tool: sarifTool{
+					Driver: sarifDriver{
+						Name:  "subsnipe",
+						Rules: []sarifRule{{ID: sarifRuleID, Name: "PossibleSubdomainTakeover"}},
+					},
+				},
+			},
+		},
+	}
+
+	for _, f := range findings {
+		category := findingCategory(f)
+		if category == "notExploitable" {
+			continue
+		}
+
+		level := "note"
+		if category == "exploitable" {
+			level = "error"
+		} else if category == "unknown" {
+			level = "warning"
+		}
+
+		report.Runs[0].Results = append(report.Runs[0].Results, sarifResult{
+			RuleID:  sarifRuleID,
+			Level:   level,
+			Message: sarifMessage{Text: formatFindingLine(f)},
+			Locations: []sarifLocation{
+				{LogicalLocations: []sarifLogicalLocation{{Name: f.Domain, Kind: "member"}}},
+			},
+		})
+	}
+
+	encoder := json.NewEncoder(outputFile)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(report)
+}
@@ -0,0 +1,76 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// AlienVaultSource enumerates subdomains via AlienVault OTX's passive DNS API.
+type AlienVaultSource struct{}
+
+// NewAlienVaultSource creates an AlienVaultSource.
+func NewAlienVaultSource() *AlienVaultSource {
+	return &AlienVaultSource{}
+}
+
+func (s *AlienVaultSource) Name() string {
+	return "otx"
+}
+
+type otxPassiveDNSResponse struct {
+	PassiveDNS []struct {
+		Hostname string `json:"hostname"`
+	} `json:"passive_dns"`
+}
+
+func (s *AlienVaultSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://otx.alienvault.com/api/v1/indicators/domain/%s/passive_dns", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building OTX request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying OTX: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading OTX response: %w", err)
+	}
+
+	var parsed otxPassiveDNSResponse
+	if err := json.Unmarshal(body, &parsed); err != nil {
+		return nil, fmt.Errorf("unmarshaling OTX response: %w", err)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		seen := make(map[string]struct{})
+		for _, record := range parsed.PassiveDNS {
+			name := strings.ToLower(strings.TrimSuffix(record.Hostname, "."))
+			if name == "" || !isSubdomainOf(name, domain) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,87 @@
+package sources
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// CRTSHSource enumerates subdomains by querying crt.sh's certificate transparency search.
+type CRTSHSource struct{}
+
+// NewCRTSHSource creates a CRTSHSource.
+func NewCRTSHSource() *CRTSHSource {
+	return &CRTSHSource{}
+}
+
+func (s *CRTSHSource) Name() string {
+	return "crtsh"
+}
+
+func (s *CRTSHSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://crt.sh/?q=%s&output=json", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building crt.sh request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying crt.sh: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading crt.sh response: %w", err)
+	}
+
+	var entries []map[string]interface{}
+	if err := json.Unmarshal(body, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshaling crt.sh response: %w", err)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		seen := make(map[string]struct{})
+		emit := func(raw string) bool {
+			name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(raw, "*.")))
+			if name == "" || !isSubdomainOf(name, domain) {
+				return true
+			}
+			if _, ok := seen[name]; ok {
+				return true
+			}
+			seen[name] = struct{}{}
+
+			select {
+			case out <- name:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		for _, entry := range entries {
+			commonName, _ := entry["common_name"].(string)
+			for _, name := range strings.Split(commonName, "\n") {
+				if !emit(name) {
+					return
+				}
+			}
+
+			nameValue, _ := entry["name_value"].(string)
+			for _, name := range strings.Split(nameValue, "\n") {
+				if !emit(name) {
+					return
+				}
+			}
+		}
+	}()
+
+	return out, nil
+}
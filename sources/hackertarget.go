@@ -0,0 +1,58 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// HackerTargetSource enumerates subdomains via the HackerTarget hostsearch API.
+type HackerTargetSource struct{}
+
+// NewHackerTargetSource creates a HackerTargetSource.
+func NewHackerTargetSource() *HackerTargetSource {
+	return &HackerTargetSource{}
+}
+
+func (s *HackerTargetSource) Name() string {
+	return "hackertarget"
+}
+
+func (s *HackerTargetSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://api.hackertarget.com/hostsearch/?q=%s", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building HackerTarget request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying HackerTarget: %w", err)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer resp.Body.Close()
+		defer close(out)
+
+		// Each line is "subdomain,ip"; a quota error comes back as a single line of text.
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			name := strings.ToLower(strings.SplitN(line, ",", 2)[0])
+			if name == "" || !isSubdomainOf(name, domain) {
+				continue
+			}
+
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
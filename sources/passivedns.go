@@ -0,0 +1,53 @@
+package sources
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PassiveDNSSource enumerates subdomains from a local newline-separated passive DNS file,
+// for engagements where a team already maintains its own passive DNS corpus.
+type PassiveDNSSource struct {
+	path string
+}
+
+// NewPassiveDNSSource creates a PassiveDNSSource reading from path.
+func NewPassiveDNSSource(path string) *PassiveDNSSource {
+	return &PassiveDNSSource{path: path}
+}
+
+func (s *PassiveDNSSource) Name() string {
+	return "passivedns"
+}
+
+func (s *PassiveDNSSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("opening passive DNS file: %w", err)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer file.Close()
+		defer close(out)
+
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			name := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if name == "" || !isSubdomainOf(name, domain) {
+				continue
+			}
+
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
@@ -0,0 +1,68 @@
+package sources
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// RapidDNSSource enumerates subdomains by scraping RapidDNS's subdomain search page.
+type RapidDNSSource struct{}
+
+// NewRapidDNSSource creates a RapidDNSSource.
+func NewRapidDNSSource() *RapidDNSSource {
+	return &RapidDNSSource{}
+}
+
+func (s *RapidDNSSource) Name() string {
+	return "rapiddns"
+}
+
+// rapidDNSRowPattern extracts the subdomain cell out of RapidDNS's results table.
+var rapidDNSRowPattern = regexp.MustCompile(`<td>([a-zA-Z0-9.\-]+)</td>`)
+
+func (s *RapidDNSSource) Enumerate(ctx context.Context, domain string) (<-chan string, error) {
+	url := fmt.Sprintf("https://rapiddns.io/subdomain/%s?full=1", domain)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("building RapidDNS request: %w", err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("querying RapidDNS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading RapidDNS response: %w", err)
+	}
+
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		seen := make(map[string]struct{})
+		for _, match := range rapidDNSRowPattern.FindAllStringSubmatch(string(body), -1) {
+			name := strings.ToLower(match[1])
+			if !isSubdomainOf(name, domain) {
+				continue
+			}
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+
+			select {
+			case out <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out, nil
+}
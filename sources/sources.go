@@ -0,0 +1,66 @@
+// Package sources provides pluggable subdomain-enumeration backends for SubSnipe.
+//
+// Each EnumSource streams candidate subdomains for a given domain over a channel so that
+// results from multiple sources can be merged and de-duplicated before being handed off to
+// the CNAME-checking pipeline.
+package sources
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// EnumSource is implemented by every subdomain enumeration backend SubSnipe can query.
+type EnumSource interface {
+	// Name returns the short identifier used in --sources and log output (e.g. "crtsh").
+	Name() string
+	// Enumerate queries the source for subdomains of domain, streaming results on the
+	// returned channel until the source is exhausted or ctx is cancelled.
+	Enumerate(ctx context.Context, domain string) (<-chan string, error)
+}
+
+// httpClient is shared by every HTTP-backed source so that one unresponsive upstream (e.g.
+// RapidDNS down) can't hang its request past a bounded timeout, independent of ctx - ctx is
+// only cancelled on Ctrl-C, which would otherwise leave a stuck request blocking that source's
+// goroutine indefinitely.
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Available lists the enumeration sources that can be selected via --sources by name.
+// PassiveDNSSource is deliberately excluded here because it also requires a file path;
+// callers that want it should construct it with NewPassiveDNSSource directly.
+func Available() map[string]func() EnumSource {
+	return map[string]func() EnumSource{
+		"crtsh":        func() EnumSource { return NewCRTSHSource() },
+		"hackertarget": func() EnumSource { return NewHackerTargetSource() },
+		"otx":          func() EnumSource { return NewAlienVaultSource() },
+		"rapiddns":     func() EnumSource { return NewRapidDNSSource() },
+	}
+}
+
+// isSubdomainOf reports whether name is domain itself or a subdomain of it, anchored on the
+// label boundary so lookalike siblings (e.g. "notexample.com" or "evil-example.com" for domain
+// "example.com") aren't mistaken for real subdomains and fed into the CNAME/takeover pipeline.
+func isSubdomainOf(name, domain string) bool {
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// Resolve turns a list of source names (as passed to --sources) into EnumSource instances.
+func Resolve(names []string) ([]EnumSource, error) {
+	avail := Available()
+	resolved := make([]EnumSource, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		factory, ok := avail[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown enumeration source %q", name)
+		}
+		resolved = append(resolved, factory())
+	}
+	return resolved, nil
+}